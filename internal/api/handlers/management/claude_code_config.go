@@ -10,20 +10,13 @@ import (
 )
 
 // ClaudeCodeConfig represents the model mapping configuration for Claude Code CLI.
+// The title/desc/options tags drive the reflection-based schema built by
+// BuildClaudeConfigSchema, so a generic settings UI can render this struct
+// without hard-coding each field.
 type ClaudeCodeConfig struct {
-	OpusModel   string `json:"opus_model"`
-	SonnetModel string `json:"sonnet_model"`
-	HaikuModel  string `json:"haiku_model"`
-}
-
-// ClaudeCodeSettings represents the full ~/.claude/settings.json structure.
-// We only modify the model fields while preserving other settings.
-type ClaudeCodeSettings struct {
-	OpusModel   string `json:"opus_model,omitempty"`
-	SonnetModel string `json:"sonnet_model,omitempty"`
-	HaikuModel  string `json:"haiku_model,omitempty"`
-	// Use json.RawMessage to preserve unknown fields
-	extra map[string]json.RawMessage
+	OpusModel   string `json:"opus_model" title:"Opus Model" desc:"Model ID the proxy routes Claude Code's 'opus' tier requests to." options:"models"`
+	SonnetModel string `json:"sonnet_model" title:"Sonnet Model" desc:"Model ID the proxy routes Claude Code's 'sonnet' tier requests to." options:"models"`
+	HaikuModel  string `json:"haiku_model" title:"Haiku Model" desc:"Model ID the proxy routes Claude Code's 'haiku' tier requests to." options:"models"`
 }
 
 func getClaudeSettingsPath() string {
@@ -42,40 +35,21 @@ func (h *Handler) GetClaudeCodeConfig(c *gin.Context) {
 		return
 	}
 
-	data, err := os.ReadFile(settingsPath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			c.JSON(200, ClaudeCodeConfig{})
-			return
-		}
-		c.JSON(500, gin.H{"error": "failed to read settings file"})
-		return
-	}
-
-	var raw map[string]json.RawMessage
-	if err := json.Unmarshal(data, &raw); err != nil {
+	store := NewClaudeSettingsStore(settingsPath)
+	if err := store.Load(); err != nil {
 		c.JSON(200, ClaudeCodeConfig{})
 		return
 	}
 
 	config := ClaudeCodeConfig{}
-	if opusRaw, ok := raw["opus_model"]; ok {
-		var opus string
-		if json.Unmarshal(opusRaw, &opus) == nil {
-			config.OpusModel = opus
-		}
+	if raw, ok, _ := store.Get("opus_model"); ok {
+		_ = json.Unmarshal(raw, &config.OpusModel)
 	}
-	if sonnetRaw, ok := raw["sonnet_model"]; ok {
-		var sonnet string
-		if json.Unmarshal(sonnetRaw, &sonnet) == nil {
-			config.SonnetModel = sonnet
-		}
+	if raw, ok, _ := store.Get("sonnet_model"); ok {
+		_ = json.Unmarshal(raw, &config.SonnetModel)
 	}
-	if haikuRaw, ok := raw["haiku_model"]; ok {
-		var haiku string
-		if json.Unmarshal(haikuRaw, &haiku) == nil {
-			config.HaikuModel = haiku
-		}
+	if raw, ok, _ := store.Get("haiku_model"); ok {
+		_ = json.Unmarshal(raw, &config.HaikuModel)
 	}
 
 	c.JSON(200, config)
@@ -95,62 +69,111 @@ func (h *Handler) PutClaudeCodeConfig(c *gin.Context) {
 		return
 	}
 
-	// Ensure directory exists
-	settingsDir := filepath.Dir(settingsPath)
-	if err := os.MkdirAll(settingsDir, 0755); err != nil {
-		c.JSON(500, gin.H{"error": "failed to create settings directory"})
-		return
-	}
+	input.OpusModel = strings.TrimSpace(input.OpusModel)
+	input.SonnetModel = strings.TrimSpace(input.SonnetModel)
+	input.HaikuModel = strings.TrimSpace(input.HaikuModel)
 
-	// Read existing settings to preserve other fields
-	var existing map[string]json.RawMessage
-	data, err := os.ReadFile(settingsPath)
-	if err != nil {
-		if !os.IsNotExist(err) {
-			c.JSON(500, gin.H{"error": "failed to read settings file"})
-			return
-		}
-		existing = make(map[string]json.RawMessage)
-	} else {
-		if err := json.Unmarshal(data, &existing); err != nil {
-			existing = make(map[string]json.RawMessage)
+	store := NewClaudeSettingsStore(settingsPath)
+	err := store.Update(func(s *ClaudeSettingsStore) error {
+		for path, value := range map[string]string{
+			"opus_model":   input.OpusModel,
+			"sonnet_model": input.SonnetModel,
+			"haiku_model":  input.HaikuModel,
+		} {
+			if value != "" {
+				if err := s.Patch(path, value); err != nil {
+					return err
+				}
+			} else if err := s.Delete(path); err != nil {
+				return err
+			}
 		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(500, gin.H{"error": "failed to write settings file"})
+		return
 	}
 
-	// Update model fields
-	input.OpusModel = strings.TrimSpace(input.OpusModel)
-	input.SonnetModel = strings.TrimSpace(input.SonnetModel)
-	input.HaikuModel = strings.TrimSpace(input.HaikuModel)
+	c.JSON(200, gin.H{"success": true})
+}
 
-	if input.OpusModel != "" {
-		raw, _ := json.Marshal(input.OpusModel)
-		existing["opus_model"] = raw
-	} else {
-		delete(existing, "opus_model")
+// GetClaudeSettingsField returns the raw JSON value stored at an arbitrary
+// dotted path inside settings.json, e.g.
+// GET /api/management/claude/settings/field/env.ANTHROPIC_API_KEY. It's
+// mounted under its own "field" segment rather than directly on
+// .../settings/*path so the wildcard doesn't collide with the static
+// .../settings/backups routes in claude_settings_backup.go.
+func (h *Handler) GetClaudeSettingsField(c *gin.Context) {
+	path := strings.Trim(c.Param("path"), "/")
+	if path == "" {
+		c.JSON(400, gin.H{"error": "path is required"})
+		return
 	}
 
-	if input.SonnetModel != "" {
-		raw, _ := json.Marshal(input.SonnetModel)
-		existing["sonnet_model"] = raw
-	} else {
-		delete(existing, "sonnet_model")
+	settingsPath := getClaudeSettingsPath()
+	if settingsPath == "" {
+		c.JSON(500, gin.H{"error": "failed to determine home directory"})
+		return
 	}
 
-	if input.HaikuModel != "" {
-		raw, _ := json.Marshal(input.HaikuModel)
-		existing["haiku_model"] = raw
-	} else {
-		delete(existing, "haiku_model")
+	store := NewClaudeSettingsStore(settingsPath)
+	if err := store.Load(); err != nil {
+		c.JSON(500, gin.H{"error": "failed to read settings file"})
+		return
 	}
 
-	// Write back
-	output, err := json.MarshalIndent(existing, "", "  ")
+	value, ok, err := store.Get(path)
 	if err != nil {
-		c.JSON(500, gin.H{"error": "failed to marshal settings"})
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	if !ok {
+		c.JSON(404, gin.H{"error": "path not found"})
 		return
 	}
 
-	if err := os.WriteFile(settingsPath, output, 0644); err != nil {
+	c.Data(200, "application/json", value)
+}
+
+// PutClaudeSettingsField sets an arbitrary dotted path inside settings.json
+// to the JSON value in the request body, creating intermediate objects as
+// needed, e.g. PUT /api/management/claude/settings/field/permissions.allow[0].
+// See GetClaudeSettingsField for why this lives under "field/*path" rather
+// than "*path" directly.
+func (h *Handler) PutClaudeSettingsField(c *gin.Context) {
+	path := strings.Trim(c.Param("path"), "/")
+	if path == "" {
+		c.JSON(400, gin.H{"error": "path is required"})
+		return
+	}
+
+	var value interface{}
+	if err := c.ShouldBindJSON(&value); err != nil {
+		c.JSON(400, gin.H{"error": "invalid body"})
+		return
+	}
+
+	settingsPath := getClaudeSettingsPath()
+	if settingsPath == "" {
+		c.JSON(500, gin.H{"error": "failed to determine home directory"})
+		return
+	}
+
+	store := NewClaudeSettingsStore(settingsPath)
+	var patchErr error
+	err := store.Update(func(s *ClaudeSettingsStore) error {
+		if err := s.Patch(path, value); err != nil {
+			patchErr = err
+			return err
+		}
+		return nil
+	})
+	if patchErr != nil {
+		c.JSON(400, gin.H{"error": patchErr.Error()})
+		return
+	}
+	if err != nil {
 		c.JSON(500, gin.H{"error": "failed to write settings file"})
 		return
 	}
@@ -0,0 +1,72 @@
+package management
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ModelCatalog returns the model IDs the proxy currently knows how to
+// route to. It's a var rather than a direct import so the upstream model
+// registry package can inject the real catalog at startup, e.g.:
+//
+//	management.ModelCatalog = registry.KnownModelIDs
+//
+// Left unwired, it reports no options rather than guessing at model IDs.
+var ModelCatalog = func() []string { return nil }
+
+// ClaudeConfigFieldSchema describes one ClaudeCodeConfig field for a
+// generic settings UI: where it lives, what kind of value it holds, and
+// how it should be labeled and validated.
+type ClaudeConfigFieldSchema struct {
+	Path        string   `json:"path"`
+	Type        string   `json:"type"`
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	Options     []string `json:"options,omitempty"`
+	Default     string   `json:"default,omitempty"`
+	Required    bool     `json:"required"`
+	ReadOnly    bool     `json:"readonly"`
+}
+
+// BuildClaudeConfigSchema reflects over ClaudeCodeConfig's struct tags to
+// produce a descriptor a frontend can render as a form, so adding a field
+// to the Go struct is enough to surface it without a matching UI change.
+func BuildClaudeConfigSchema() []ClaudeConfigFieldSchema {
+	t := reflect.TypeOf(ClaudeCodeConfig{})
+	schema := make([]ClaudeConfigFieldSchema, 0, t.NumField())
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		path, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if path == "" || path == "-" {
+			continue
+		}
+
+		fieldSchema := ClaudeConfigFieldSchema{
+			Path:        path,
+			Type:        field.Type.Kind().String(),
+			Title:       field.Tag.Get("title"),
+			Description: field.Tag.Get("desc"),
+			Default:     field.Tag.Get("default"),
+			Required:    field.Tag.Get("required") == "true",
+			ReadOnly:    field.Tag.Get("readonly") == "true",
+		}
+
+		if field.Tag.Get("options") == "models" {
+			fieldSchema.Options = ModelCatalog()
+		}
+
+		schema = append(schema, fieldSchema)
+	}
+
+	return schema
+}
+
+// GetClaudeConfigSchema returns a descriptor of every field
+// ClaudeCodeConfig supports, for a generic settings form.
+func (h *Handler) GetClaudeConfigSchema(c *gin.Context) {
+	c.JSON(200, gin.H{"fields": BuildClaudeConfigSchema()})
+}
@@ -0,0 +1,347 @@
+package management
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ClaudeProfile is a named Opus/Sonnet/Haiku mapping, so a user can switch
+// between e.g. a "cheap local" profile and a "production Anthropic"
+// profile without re-typing model IDs.
+type ClaudeProfile struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Mapping     ClaudeCodeConfig `json:"mapping"`
+	CreatedAt   time.Time        `json:"created_at"`
+}
+
+func getClaudeProfilesPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".claude", "one-proxy", "profiles.json")
+}
+
+// loadClaudeProfiles reads the profiles file, treating a missing file as
+// no profiles yet.
+func loadClaudeProfiles(path string) ([]ClaudeProfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return nil, nil
+	}
+
+	var profiles []ClaudeProfile
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return nil, fmt.Errorf("parse profiles file: %w", err)
+	}
+	return profiles, nil
+}
+
+// saveClaudeProfiles writes the profiles file atomically under an advisory
+// lock on the profiles file itself (a sibling profiles.json.lock). Only the
+// write is locked: the handlers load the profiles before taking it, and
+// activation takes the settings.json lock rather than this one.
+func saveClaudeProfiles(path string, profiles []ClaudeProfile) error {
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].Name < profiles[j].Name })
+
+	output, err := json.MarshalIndent(profiles, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal profiles: %w", err)
+	}
+
+	return withFileLock(path, func() error {
+		return atomicWriteFile(path, output, 0644)
+	})
+}
+
+func findClaudeProfile(profiles []ClaudeProfile, name string) int {
+	for i, p := range profiles {
+		if p.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// validateClaudeProfileMapping rejects any non-empty model ID not present
+// in the proxy's known model catalog. management.ModelCatalog must be
+// wired to the upstream registry for this to mean anything; until it is,
+// any tier that's actually set fails closed with a message that says so,
+// rather than silently accepting an arbitrary model ID. A mapping with no
+// models set at all needs no catalog and always passes.
+func validateClaudeProfileMapping(mapping ClaudeCodeConfig) error {
+	var knownSet map[string]bool
+
+	for _, target := range []struct {
+		tier  string
+		model string
+	}{
+		{"opus_model", mapping.OpusModel},
+		{"sonnet_model", mapping.SonnetModel},
+		{"haiku_model", mapping.HaikuModel},
+	} {
+		if target.model == "" {
+			continue
+		}
+
+		if knownSet == nil {
+			known := ModelCatalog()
+			if len(known) == 0 {
+				return fmt.Errorf("model catalog is not configured: wire management.ModelCatalog to the proxy's upstream model registry before activating profiles")
+			}
+			knownSet = make(map[string]bool, len(known))
+			for _, id := range known {
+				knownSet[id] = true
+			}
+		}
+
+		if !knownSet[target.model] {
+			return fmt.Errorf("%s: %q is not a known model", target.tier, target.model)
+		}
+	}
+
+	return nil
+}
+
+// ListClaudeProfiles returns all saved profiles.
+func (h *Handler) ListClaudeProfiles(c *gin.Context) {
+	profiles, err := loadClaudeProfiles(getClaudeProfilesPath())
+	if err != nil {
+		c.JSON(500, gin.H{"error": "failed to load profiles"})
+		return
+	}
+	c.JSON(200, gin.H{"profiles": profiles})
+}
+
+// CreateClaudeProfile adds a new profile.
+func (h *Handler) CreateClaudeProfile(c *gin.Context) {
+	var input ClaudeProfile
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(400, gin.H{"error": "invalid body"})
+		return
+	}
+
+	input.Name = strings.TrimSpace(input.Name)
+	if input.Name == "" {
+		c.JSON(400, gin.H{"error": "name is required"})
+		return
+	}
+	if err := validateClaudeProfileMapping(input.Mapping); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	profilesPath := getClaudeProfilesPath()
+	profiles, err := loadClaudeProfiles(profilesPath)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "failed to load profiles"})
+		return
+	}
+	if findClaudeProfile(profiles, input.Name) >= 0 {
+		c.JSON(409, gin.H{"error": "a profile with this name already exists"})
+		return
+	}
+
+	input.CreatedAt = time.Now().UTC()
+	profiles = append(profiles, input)
+
+	if err := saveClaudeProfiles(profilesPath, profiles); err != nil {
+		c.JSON(500, gin.H{"error": "failed to save profiles"})
+		return
+	}
+
+	c.JSON(200, input)
+}
+
+// UpdateClaudeProfile replaces the description and mapping of an existing
+// profile, identified by :name.
+func (h *Handler) UpdateClaudeProfile(c *gin.Context) {
+	name := c.Param("name")
+
+	var input ClaudeProfile
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(400, gin.H{"error": "invalid body"})
+		return
+	}
+	if err := validateClaudeProfileMapping(input.Mapping); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	profilesPath := getClaudeProfilesPath()
+	profiles, err := loadClaudeProfiles(profilesPath)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "failed to load profiles"})
+		return
+	}
+
+	idx := findClaudeProfile(profiles, name)
+	if idx < 0 {
+		c.JSON(404, gin.H{"error": "profile not found"})
+		return
+	}
+
+	profiles[idx].Description = input.Description
+	profiles[idx].Mapping = input.Mapping
+
+	if err := saveClaudeProfiles(profilesPath, profiles); err != nil {
+		c.JSON(500, gin.H{"error": "failed to save profiles"})
+		return
+	}
+
+	c.JSON(200, profiles[idx])
+}
+
+// DeleteClaudeProfile removes a profile by :name.
+func (h *Handler) DeleteClaudeProfile(c *gin.Context) {
+	name := c.Param("name")
+
+	profilesPath := getClaudeProfilesPath()
+	profiles, err := loadClaudeProfiles(profilesPath)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "failed to load profiles"})
+		return
+	}
+
+	idx := findClaudeProfile(profiles, name)
+	if idx < 0 {
+		c.JSON(404, gin.H{"error": "profile not found"})
+		return
+	}
+	profiles = append(profiles[:idx], profiles[idx+1:]...)
+
+	if err := saveClaudeProfiles(profilesPath, profiles); err != nil {
+		c.JSON(500, gin.H{"error": "failed to save profiles"})
+		return
+	}
+
+	c.JSON(200, gin.H{"success": true})
+}
+
+// ActivateClaudeProfile applies a profile's mapping to settings.json via
+// the atomic settings store, so activation gets the same crash-safety and
+// backup guarantees as a manual config edit.
+func (h *Handler) ActivateClaudeProfile(c *gin.Context) {
+	name := c.Param("name")
+
+	profiles, err := loadClaudeProfiles(getClaudeProfilesPath())
+	if err != nil {
+		c.JSON(500, gin.H{"error": "failed to load profiles"})
+		return
+	}
+
+	idx := findClaudeProfile(profiles, name)
+	if idx < 0 {
+		c.JSON(404, gin.H{"error": "profile not found"})
+		return
+	}
+	mapping := profiles[idx].Mapping
+
+	if err := validateClaudeProfileMapping(mapping); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	settingsPath := getClaudeSettingsPath()
+	if settingsPath == "" {
+		c.JSON(500, gin.H{"error": "failed to determine home directory"})
+		return
+	}
+
+	store := NewClaudeSettingsStore(settingsPath)
+	err = store.Update(func(s *ClaudeSettingsStore) error {
+		for path, value := range map[string]string{
+			"opus_model":   mapping.OpusModel,
+			"sonnet_model": mapping.SonnetModel,
+			"haiku_model":  mapping.HaikuModel,
+		} {
+			if value != "" {
+				if err := s.Patch(path, value); err != nil {
+					return err
+				}
+			} else if err := s.Delete(path); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(500, gin.H{"error": "failed to write settings file"})
+		return
+	}
+
+	c.JSON(200, gin.H{"success": true, "activated": name})
+}
+
+// ImportClaudeProfiles adds or replaces profiles from a JSON array in the
+// request body, for sharing profiles between machines.
+func (h *Handler) ImportClaudeProfiles(c *gin.Context) {
+	var imported []ClaudeProfile
+	if err := c.ShouldBindJSON(&imported); err != nil {
+		c.JSON(400, gin.H{"error": "invalid body"})
+		return
+	}
+
+	for i := range imported {
+		imported[i].Name = strings.TrimSpace(imported[i].Name)
+		if imported[i].Name == "" {
+			c.JSON(400, gin.H{"error": "every profile needs a name"})
+			return
+		}
+		if err := validateClaudeProfileMapping(imported[i].Mapping); err != nil {
+			c.JSON(400, gin.H{"error": fmt.Sprintf("%s: %s", imported[i].Name, err.Error())})
+			return
+		}
+		if imported[i].CreatedAt.IsZero() {
+			imported[i].CreatedAt = time.Now().UTC()
+		}
+	}
+
+	profilesPath := getClaudeProfilesPath()
+	profiles, err := loadClaudeProfiles(profilesPath)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "failed to load profiles"})
+		return
+	}
+
+	for _, p := range imported {
+		if idx := findClaudeProfile(profiles, p.Name); idx >= 0 {
+			profiles[idx] = p
+		} else {
+			profiles = append(profiles, p)
+		}
+	}
+
+	if err := saveClaudeProfiles(profilesPath, profiles); err != nil {
+		c.JSON(500, gin.H{"error": "failed to save profiles"})
+		return
+	}
+
+	c.JSON(200, gin.H{"imported": len(imported)})
+}
+
+// ExportClaudeProfiles returns all profiles as a JSON array suitable for
+// ImportClaudeProfiles on another machine.
+func (h *Handler) ExportClaudeProfiles(c *gin.Context) {
+	profiles, err := loadClaudeProfiles(getClaudeProfilesPath())
+	if err != nil {
+		c.JSON(500, gin.H{"error": "failed to load profiles"})
+		return
+	}
+	c.JSON(200, profiles)
+}
@@ -0,0 +1,209 @@
+package management
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func withClaudeHome(t *testing.T) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+}
+
+func withModelCatalog(t *testing.T, models []string) {
+	t.Helper()
+	previous := ModelCatalog
+	ModelCatalog = func() []string { return models }
+	t.Cleanup(func() { ModelCatalog = previous })
+}
+
+func newTestContext(t *testing.T, method string, body interface{}, params gin.Params) (*gin.Context, *httptest.ResponseRecorder) {
+	t.Helper()
+
+	var reader *bytes.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("marshal request body: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(method, "/", reader)
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = params
+
+	return c, w
+}
+
+func TestValidateClaudeProfileMapping(t *testing.T) {
+	t.Run("empty mapping needs no catalog", func(t *testing.T) {
+		withModelCatalog(t, nil)
+		if err := validateClaudeProfileMapping(ClaudeCodeConfig{}); err != nil {
+			t.Fatalf("validateClaudeProfileMapping(empty) = %v, want nil", err)
+		}
+	})
+
+	t.Run("non-empty mapping fails closed with no catalog", func(t *testing.T) {
+		withModelCatalog(t, nil)
+		err := validateClaudeProfileMapping(ClaudeCodeConfig{OpusModel: "claude-3-5-sonnet"})
+		if err == nil {
+			t.Fatal("validateClaudeProfileMapping(unconfigured catalog) = nil, want an error")
+		}
+	})
+
+	t.Run("known model passes", func(t *testing.T) {
+		withModelCatalog(t, []string{"claude-3-5-sonnet", "claude-3-5-haiku"})
+		mapping := ClaudeCodeConfig{SonnetModel: "claude-3-5-sonnet", HaikuModel: "claude-3-5-haiku"}
+		if err := validateClaudeProfileMapping(mapping); err != nil {
+			t.Fatalf("validateClaudeProfileMapping(known) = %v, want nil", err)
+		}
+	})
+
+	t.Run("unknown model is rejected", func(t *testing.T) {
+		withModelCatalog(t, []string{"claude-3-5-sonnet"})
+		mapping := ClaudeCodeConfig{OpusModel: "made-up-model"}
+		if err := validateClaudeProfileMapping(mapping); err == nil {
+			t.Fatal("validateClaudeProfileMapping(unknown) = nil, want an error")
+		}
+	})
+}
+
+func TestClaudeProfileCRUD(t *testing.T) {
+	withClaudeHome(t)
+	withModelCatalog(t, []string{"claude-3-5-sonnet", "claude-3-5-haiku"})
+	h := &Handler{}
+
+	create, w := newTestContext(t, http.MethodPost, ClaudeProfile{
+		Name:        "prod",
+		Description: "production Anthropic models",
+		Mapping:     ClaudeCodeConfig{SonnetModel: "claude-3-5-sonnet"},
+	}, nil)
+	h.CreateClaudeProfile(create)
+	if w.Code != 200 {
+		t.Fatalf("CreateClaudeProfile status = %d, body = %s", w.Code, w.Body.String())
+	}
+
+	list, w := newTestContext(t, http.MethodGet, nil, nil)
+	h.ListClaudeProfiles(list)
+	var listResp struct {
+		Profiles []ClaudeProfile `json:"profiles"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &listResp); err != nil {
+		t.Fatalf("decode list response: %v", err)
+	}
+	if len(listResp.Profiles) != 1 || listResp.Profiles[0].Name != "prod" {
+		t.Fatalf("ListClaudeProfiles = %+v, want one profile named prod", listResp.Profiles)
+	}
+
+	update, w := newTestContext(t, http.MethodPut, ClaudeProfile{
+		Description: "updated description",
+		Mapping:     ClaudeCodeConfig{SonnetModel: "claude-3-5-sonnet", HaikuModel: "claude-3-5-haiku"},
+	}, gin.Params{{Key: "name", Value: "prod"}})
+	h.UpdateClaudeProfile(update)
+	if w.Code != 200 {
+		t.Fatalf("UpdateClaudeProfile status = %d, body = %s", w.Code, w.Body.String())
+	}
+
+	activate, w := newTestContext(t, http.MethodPost, nil, gin.Params{{Key: "name", Value: "prod"}})
+	h.ActivateClaudeProfile(activate)
+	if w.Code != 200 {
+		t.Fatalf("ActivateClaudeProfile status = %d, body = %s", w.Code, w.Body.String())
+	}
+
+	settingsStore := NewClaudeSettingsStore(getClaudeSettingsPath())
+	if err := settingsStore.Load(); err != nil {
+		t.Fatalf("load settings after activate: %v", err)
+	}
+	if raw, ok, _ := settingsStore.Get("sonnet_model"); !ok || string(raw) != `"claude-3-5-sonnet"` {
+		t.Fatalf("sonnet_model after activate = %s, ok=%v, want claude-3-5-sonnet", raw, ok)
+	}
+	if raw, ok, _ := settingsStore.Get("haiku_model"); !ok || string(raw) != `"claude-3-5-haiku"` {
+		t.Fatalf("haiku_model after activate = %s, ok=%v, want claude-3-5-haiku", raw, ok)
+	}
+
+	exportCtx, w := newTestContext(t, http.MethodGet, nil, nil)
+	h.ExportClaudeProfiles(exportCtx)
+	var exported []ClaudeProfile
+	if err := json.Unmarshal(w.Body.Bytes(), &exported); err != nil {
+		t.Fatalf("decode export response: %v", err)
+	}
+	if len(exported) != 1 || exported[0].Name != "prod" {
+		t.Fatalf("ExportClaudeProfiles = %+v, want one profile named prod", exported)
+	}
+
+	del, w := newTestContext(t, http.MethodDelete, nil, gin.Params{{Key: "name", Value: "prod"}})
+	h.DeleteClaudeProfile(del)
+	if w.Code != 200 {
+		t.Fatalf("DeleteClaudeProfile status = %d, body = %s", w.Code, w.Body.String())
+	}
+
+	afterDelete, w := newTestContext(t, http.MethodGet, nil, nil)
+	h.ListClaudeProfiles(afterDelete)
+	listResp.Profiles = nil
+	if err := json.Unmarshal(w.Body.Bytes(), &listResp); err != nil {
+		t.Fatalf("decode list response after delete: %v", err)
+	}
+	if len(listResp.Profiles) != 0 {
+		t.Fatalf("ListClaudeProfiles after delete = %+v, want none", listResp.Profiles)
+	}
+}
+
+func TestImportClaudeProfilesRejectsUnknownModel(t *testing.T) {
+	withClaudeHome(t)
+	withModelCatalog(t, []string{"claude-3-5-sonnet"})
+	h := &Handler{}
+
+	importCtx, w := newTestContext(t, http.MethodPost, []ClaudeProfile{
+		{Name: "bad", Mapping: ClaudeCodeConfig{OpusModel: "made-up-model"}},
+	}, nil)
+	h.ImportClaudeProfiles(importCtx)
+	if w.Code != 400 {
+		t.Fatalf("ImportClaudeProfiles(unknown model) status = %d, want 400", w.Code)
+	}
+
+	profiles, err := loadClaudeProfiles(getClaudeProfilesPath())
+	if err != nil {
+		t.Fatalf("loadClaudeProfiles: %v", err)
+	}
+	if len(profiles) != 0 {
+		t.Fatalf("profiles = %+v, want none persisted after a rejected import", profiles)
+	}
+}
+
+func TestImportThenExportClaudeProfilesRoundTrip(t *testing.T) {
+	withClaudeHome(t)
+	withModelCatalog(t, []string{"claude-3-5-sonnet"})
+	h := &Handler{}
+
+	importCtx, w := newTestContext(t, http.MethodPost, []ClaudeProfile{
+		{Name: "shared", Mapping: ClaudeCodeConfig{SonnetModel: "claude-3-5-sonnet"}},
+	}, nil)
+	h.ImportClaudeProfiles(importCtx)
+	if w.Code != 200 {
+		t.Fatalf("ImportClaudeProfiles status = %d, body = %s", w.Code, w.Body.String())
+	}
+
+	exportCtx, w := newTestContext(t, http.MethodGet, nil, nil)
+	h.ExportClaudeProfiles(exportCtx)
+	var exported []ClaudeProfile
+	if err := json.Unmarshal(w.Body.Bytes(), &exported); err != nil {
+		t.Fatalf("decode export response: %v", err)
+	}
+	if len(exported) != 1 || exported[0].Name != "shared" || exported[0].Mapping.SonnetModel != "claude-3-5-sonnet" {
+		t.Fatalf("ExportClaudeProfiles = %+v, want one shared profile", exported)
+	}
+}
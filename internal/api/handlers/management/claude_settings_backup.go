@@ -0,0 +1,243 @@
+package management
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxSettingsBackups is how many rolling settings.json snapshots we keep
+// under the backups directory before pruning the oldest.
+const maxSettingsBackups = 20
+
+const settingsBackupTimeLayout = "20060102T150405.000000000Z"
+
+// withFileLock takes an OS-level advisory lock scoped to path (via a
+// sibling .lock file) for the duration of fn, so two concurrent API calls
+// or another process editing settings.json can't interleave their writes.
+func withFileLock(path string, fn func() error) error {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("create settings directory: %w", err)
+		}
+	}
+
+	lockFileHandle, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return fmt.Errorf("open lock file: %w", err)
+	}
+	defer lockFileHandle.Close()
+
+	unlock, err := lockFile(lockFileHandle)
+	if err != nil {
+		return fmt.Errorf("lock settings file: %w", err)
+	}
+	defer unlock()
+
+	return fn()
+}
+
+// atomicWriteFile writes data to path by writing a temp file in the same
+// directory, fsyncing it, then renaming it into place, so a crash or a
+// concurrent reader never observes a truncated or partially written file.
+func atomicWriteFile(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // no-op once the rename below succeeds
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write temp file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("sync temp file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close temp file: %w", err)
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		return fmt.Errorf("chmod temp file: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("rename temp file into place: %w", err)
+	}
+	return nil
+}
+
+func claudeSettingsBackupDir(settingsPath string) string {
+	return filepath.Join(filepath.Dir(settingsPath), "settings.backups")
+}
+
+// backupSettingsFile snapshots the current settings.json (if any) into the
+// backups directory and prunes anything past maxSettingsBackups. Must be
+// called while holding the settings lock.
+func backupSettingsFile(settingsPath string) error {
+	data, err := os.ReadFile(settingsPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	backupDir := claudeSettingsBackupDir(settingsPath)
+	if err := os.MkdirAll(backupDir, 0755); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("settings-%s.json", time.Now().UTC().Format(settingsBackupTimeLayout))
+	if err := atomicWriteFile(filepath.Join(backupDir, name), data, 0644); err != nil {
+		return err
+	}
+
+	return pruneSettingsBackups(backupDir, maxSettingsBackups)
+}
+
+func pruneSettingsBackups(backupDir string, keep int) error {
+	entries, err := os.ReadDir(backupDir)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			if _, ok := parseSettingsBackupID(entry.Name()); ok {
+				names = append(names, entry.Name())
+			}
+		}
+	}
+	sort.Strings(names) // timestamp-named, so lexical order is chronological
+
+	if len(names) <= keep {
+		return nil
+	}
+	for _, stale := range names[:len(names)-keep] {
+		if err := os.Remove(filepath.Join(backupDir, stale)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parseSettingsBackupID(filename string) (string, bool) {
+	if !strings.HasPrefix(filename, "settings-") || !strings.HasSuffix(filename, ".json") {
+		return "", false
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(filename, "settings-"), ".json"), true
+}
+
+// ClaudeSettingsBackup describes one saved snapshot of settings.json.
+type ClaudeSettingsBackup struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func listSettingsBackups(backupDir string) ([]ClaudeSettingsBackup, error) {
+	entries, err := os.ReadDir(backupDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	backups := make([]ClaudeSettingsBackup, 0, len(entries))
+	for _, entry := range entries {
+		id, ok := parseSettingsBackupID(entry.Name())
+		if !ok {
+			continue
+		}
+		createdAt, err := time.Parse(settingsBackupTimeLayout, id)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, ClaudeSettingsBackup{ID: id, CreatedAt: createdAt})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].CreatedAt.After(backups[j].CreatedAt)
+	})
+
+	return backups, nil
+}
+
+// ListClaudeSettingsBackups returns the available settings.json snapshots,
+// newest first, e.g. GET /api/management/claude/settings/backups.
+func (h *Handler) ListClaudeSettingsBackups(c *gin.Context) {
+	settingsPath := getClaudeSettingsPath()
+	if settingsPath == "" {
+		c.JSON(500, gin.H{"error": "failed to determine home directory"})
+		return
+	}
+
+	backups, err := listSettingsBackups(claudeSettingsBackupDir(settingsPath))
+	if err != nil {
+		c.JSON(500, gin.H{"error": "failed to list settings backups"})
+		return
+	}
+
+	c.JSON(200, gin.H{"backups": backups})
+}
+
+// RestoreClaudeSettingsBackup overwrites settings.json with a previously
+// saved backup, snapshotting the current file first so the restore itself
+// can be undone, e.g. POST /api/management/claude/settings/backups/:id/restore.
+func (h *Handler) RestoreClaudeSettingsBackup(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(400, gin.H{"error": "backup id is required"})
+		return
+	}
+	if _, err := time.Parse(settingsBackupTimeLayout, id); err != nil {
+		c.JSON(400, gin.H{"error": "invalid backup id"})
+		return
+	}
+
+	settingsPath := getClaudeSettingsPath()
+	if settingsPath == "" {
+		c.JSON(500, gin.H{"error": "failed to determine home directory"})
+		return
+	}
+
+	backupPath := filepath.Join(claudeSettingsBackupDir(settingsPath), fmt.Sprintf("settings-%s.json", id))
+	data, err := os.ReadFile(backupPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			c.JSON(404, gin.H{"error": "backup not found"})
+			return
+		}
+		c.JSON(500, gin.H{"error": "failed to read backup"})
+		return
+	}
+
+	var parsed interface{}
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		c.JSON(500, gin.H{"error": "backup file is not valid JSON"})
+		return
+	}
+
+	err = withFileLock(settingsPath, func() error {
+		if err := backupSettingsFile(settingsPath); err != nil {
+			return fmt.Errorf("snapshot current settings: %w", err)
+		}
+		return atomicWriteFile(settingsPath, data, 0644)
+	})
+	if err != nil {
+		c.JSON(500, gin.H{"error": "failed to restore settings file"})
+		return
+	}
+
+	c.JSON(200, gin.H{"success": true})
+}
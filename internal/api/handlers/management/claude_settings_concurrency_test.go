@@ -0,0 +1,62 @@
+package management
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// TestClaudeSettingsStoreUpdateIsConcurrencySafe reproduces the lost-update
+// race that a bare Load -> Patch -> Save cycle has under concurrent
+// writers: each goroutine patches a distinct key, and every key must
+// survive if the read-modify-write cycle is actually locked end to end.
+func TestClaudeSettingsStoreUpdateIsConcurrencySafe(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.json")
+	if err := os.WriteFile(path, []byte(`{}`), 0644); err != nil {
+		t.Fatalf("seed settings file: %v", err)
+	}
+
+	const writers = 20
+	var wg sync.WaitGroup
+	errs := make([]error, writers)
+
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			store := NewClaudeSettingsStore(path)
+			errs[i] = store.Update(func(s *ClaudeSettingsStore) error {
+				return s.Patch(fmt.Sprintf("key_%d", i), i)
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("writer %d: Update: %v", i, err)
+		}
+	}
+
+	final := NewClaudeSettingsStore(path)
+	if err := final.Load(); err != nil {
+		t.Fatalf("final Load: %v", err)
+	}
+
+	for i := 0; i < writers; i++ {
+		raw, ok, err := final.Get(fmt.Sprintf("key_%d", i))
+		if err != nil {
+			t.Fatalf("Get key_%d: %v", i, err)
+		}
+		if !ok {
+			t.Fatalf("key_%d missing from final settings file: concurrent Update calls lost a write", i)
+		}
+		var value int
+		if err := json.Unmarshal(raw, &value); err != nil || value != i {
+			t.Fatalf("key_%d = %s, want %d", i, raw, i)
+		}
+	}
+}
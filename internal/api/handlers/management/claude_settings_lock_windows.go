@@ -0,0 +1,49 @@
+//go:build windows
+
+package management
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	modkernel32      = syscall.NewLazyDLL("kernel32.dll")
+	procLockFileEx   = modkernel32.NewProc("LockFileEx")
+	procUnlockFileEx = modkernel32.NewProc("UnlockFileEx")
+)
+
+const lockfileExclusiveLock = 0x2
+
+// lockFile takes an OS-level advisory exclusive lock on f, blocking until
+// it's available, and returns a function that releases it.
+func lockFile(f *os.File) (func() error, error) {
+	var overlapped syscall.Overlapped
+
+	r, _, err := procLockFileEx.Call(
+		f.Fd(),
+		uintptr(lockfileExclusiveLock),
+		0,
+		^uintptr(0),
+		^uintptr(0),
+		uintptr(unsafe.Pointer(&overlapped)),
+	)
+	if r == 0 {
+		return nil, err
+	}
+
+	return func() error {
+		r, _, err := procUnlockFileEx.Call(
+			f.Fd(),
+			0,
+			^uintptr(0),
+			^uintptr(0),
+			uintptr(unsafe.Pointer(&overlapped)),
+		)
+		if r == 0 {
+			return err
+		}
+		return nil
+	}, nil
+}
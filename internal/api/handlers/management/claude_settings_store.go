@@ -0,0 +1,414 @@
+package management
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// orderedMap preserves the key order of a JSON object across an
+// unmarshal/marshal round-trip, keeping each value's raw bytes as read, so
+// fields we don't recognize (env, permissions, hooks, ...) survive a
+// Patch/Save with their order and values intact instead of being dropped
+// or reshuffled. Save re-indents the whole file, so the original
+// whitespace is not kept.
+type orderedMap struct {
+	keys   []string
+	values map[string]json.RawMessage
+}
+
+func newOrderedMap() *orderedMap {
+	return &orderedMap{values: make(map[string]json.RawMessage)}
+}
+
+func (m *orderedMap) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(strings.NewReader(string(data)))
+
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("expected a JSON object")
+	}
+
+	m.keys = nil
+	m.values = make(map[string]json.RawMessage)
+
+	for dec.More() {
+		keyTok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, ok := keyTok.(string)
+		if !ok {
+			return fmt.Errorf("expected a string object key")
+		}
+
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return err
+		}
+		m.set(key, raw)
+	}
+
+	return nil
+}
+
+func (m *orderedMap) MarshalJSON() ([]byte, error) {
+	var buf strings.Builder
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		keyJSON, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(keyJSON)
+		buf.WriteByte(':')
+		buf.Write(m.values[key])
+	}
+	buf.WriteByte('}')
+	return []byte(buf.String()), nil
+}
+
+func (m *orderedMap) set(key string, raw json.RawMessage) {
+	if _, exists := m.values[key]; !exists {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = raw
+}
+
+func (m *orderedMap) delete(key string) {
+	if _, exists := m.values[key]; !exists {
+		return
+	}
+	delete(m.values, key)
+	for i, k := range m.keys {
+		if k == key {
+			m.keys = append(m.keys[:i], m.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+// pathSegment is one hop of a dotted JSON path: either an object key
+// ("env") or an array index ("[0]").
+type pathSegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+var pathIndexRe = regexp.MustCompile(`\[(\d+)\]`)
+
+// splitPath parses a dotted JSON path such as "env.ANTHROPIC_API_KEY" or
+// "permissions.allow[0]" into the segments used to walk an object tree.
+func splitPath(path string) []pathSegment {
+	var segments []pathSegment
+	for _, part := range strings.Split(path, ".") {
+		if part == "" {
+			continue
+		}
+		key := part
+		if idx := strings.IndexByte(part, '['); idx >= 0 {
+			key = part[:idx]
+		}
+		if key != "" {
+			segments = append(segments, pathSegment{key: key})
+		}
+		for _, m := range pathIndexRe.FindAllStringSubmatch(part, -1) {
+			n, _ := strconv.Atoi(m[1])
+			segments = append(segments, pathSegment{index: n, isIndex: true})
+		}
+	}
+	return segments
+}
+
+func getValueAt(raw json.RawMessage, segments []pathSegment) (json.RawMessage, bool, error) {
+	if len(segments) == 0 {
+		return raw, true, nil
+	}
+	seg := segments[0]
+
+	if seg.isIndex {
+		var arr []json.RawMessage
+		if err := json.Unmarshal(raw, &arr); err != nil {
+			return nil, false, fmt.Errorf("expected an array")
+		}
+		if seg.index < 0 || seg.index >= len(arr) {
+			return nil, false, nil
+		}
+		return getValueAt(arr[seg.index], segments[1:])
+	}
+
+	obj := newOrderedMap()
+	if err := json.Unmarshal(raw, obj); err != nil {
+		return nil, false, fmt.Errorf("expected an object")
+	}
+	child, ok := obj.values[seg.key]
+	if !ok {
+		return nil, false, nil
+	}
+	return getValueAt(child, segments[1:])
+}
+
+func setValueAt(raw json.RawMessage, segments []pathSegment, value json.RawMessage) (json.RawMessage, error) {
+	seg := segments[0]
+	rest := segments[1:]
+
+	if seg.isIndex {
+		var arr []json.RawMessage
+		_ = json.Unmarshal(raw, &arr) // non-array or missing: start fresh
+		for len(arr) <= seg.index {
+			arr = append(arr, json.RawMessage("null"))
+		}
+		if len(rest) == 0 {
+			arr[seg.index] = value
+		} else {
+			updated, err := setValueAt(arr[seg.index], rest, value)
+			if err != nil {
+				return nil, err
+			}
+			arr[seg.index] = updated
+		}
+		return json.Marshal(arr)
+	}
+
+	obj := newOrderedMap()
+	_ = json.Unmarshal(raw, obj) // non-object or missing: start fresh
+	if len(rest) == 0 {
+		obj.set(seg.key, value)
+	} else {
+		updated, err := setValueAt(obj.values[seg.key], rest, value)
+		if err != nil {
+			return nil, err
+		}
+		obj.set(seg.key, updated)
+	}
+	return obj.MarshalJSON()
+}
+
+func deleteValueAt(raw json.RawMessage, segments []pathSegment) (json.RawMessage, error) {
+	seg := segments[0]
+	rest := segments[1:]
+
+	if seg.isIndex {
+		var arr []json.RawMessage
+		if err := json.Unmarshal(raw, &arr); err != nil || seg.index < 0 || seg.index >= len(arr) {
+			return raw, nil
+		}
+		if len(rest) == 0 {
+			arr = append(arr[:seg.index], arr[seg.index+1:]...)
+		} else {
+			updated, err := deleteValueAt(arr[seg.index], rest)
+			if err != nil {
+				return nil, err
+			}
+			arr[seg.index] = updated
+		}
+		return json.Marshal(arr)
+	}
+
+	obj := newOrderedMap()
+	if err := json.Unmarshal(raw, obj); err != nil {
+		return raw, nil
+	}
+	if len(rest) == 0 {
+		obj.delete(seg.key)
+	} else {
+		child, ok := obj.values[seg.key]
+		if !ok {
+			return raw, nil
+		}
+		updated, err := deleteValueAt(child, rest)
+		if err != nil {
+			return nil, err
+		}
+		obj.set(seg.key, updated)
+	}
+	return obj.MarshalJSON()
+}
+
+// ClaudeSettingsStore reads and writes a Claude Code settings.json file
+// while preserving the order and contents of every key it doesn't know
+// about, so callers can patch a single nested field (e.g.
+// "env.ANTHROPIC_API_KEY" or "permissions.allow[0]") without clobbering
+// the rest of the file.
+type ClaudeSettingsStore struct {
+	path string
+	root *orderedMap
+}
+
+// NewClaudeSettingsStore creates a store bound to the settings file at path.
+func NewClaudeSettingsStore(path string) *ClaudeSettingsStore {
+	return &ClaudeSettingsStore{path: path}
+}
+
+// Load reads the settings file into memory. A missing file is treated as
+// an empty settings object so a fresh machine can still Patch and Save.
+func (s *ClaudeSettingsStore) Load() error {
+	return s.loadUnlocked()
+}
+
+func (s *ClaudeSettingsStore) loadUnlocked() error {
+	root := newOrderedMap()
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			s.root = root
+			return nil
+		}
+		return err
+	}
+
+	if len(strings.TrimSpace(string(data))) == 0 {
+		s.root = root
+		return nil
+	}
+
+	if err := json.Unmarshal(data, root); err != nil {
+		return fmt.Errorf("parse settings file: %w", err)
+	}
+
+	s.root = root
+	return nil
+}
+
+// Get resolves a dotted JSON path against the loaded settings and returns
+// the raw JSON value, or ok=false if the path doesn't exist.
+func (s *ClaudeSettingsStore) Get(path string) (json.RawMessage, bool, error) {
+	if s.root == nil {
+		return nil, false, fmt.Errorf("settings not loaded")
+	}
+	rootRaw, err := s.root.MarshalJSON()
+	if err != nil {
+		return nil, false, err
+	}
+	segments := splitPath(path)
+	if len(segments) == 0 {
+		return rootRaw, true, nil
+	}
+	return getValueAt(rootRaw, segments)
+}
+
+// Patch sets the value at the given dotted JSON path, creating
+// intermediate objects/arrays as needed. Call Save to persist the change.
+func (s *ClaudeSettingsStore) Patch(path string, value interface{}) error {
+	if s.root == nil {
+		return fmt.Errorf("settings not loaded")
+	}
+	segments := splitPath(path)
+	if len(segments) == 0 {
+		return fmt.Errorf("empty path")
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("marshal value for %q: %w", path, err)
+	}
+
+	rootRaw, err := s.root.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	updated, err := setValueAt(rootRaw, segments, raw)
+	if err != nil {
+		return fmt.Errorf("patch %q: %w", path, err)
+	}
+
+	newRoot := newOrderedMap()
+	if err := json.Unmarshal(updated, newRoot); err != nil {
+		return err
+	}
+	s.root = newRoot
+	return nil
+}
+
+// Delete removes the value at the given dotted JSON path, if present.
+func (s *ClaudeSettingsStore) Delete(path string) error {
+	if s.root == nil {
+		return fmt.Errorf("settings not loaded")
+	}
+	segments := splitPath(path)
+	if len(segments) == 0 {
+		return fmt.Errorf("empty path")
+	}
+
+	rootRaw, err := s.root.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	updated, err := deleteValueAt(rootRaw, segments)
+	if err != nil {
+		return fmt.Errorf("delete %q: %w", path, err)
+	}
+
+	newRoot := newOrderedMap()
+	if err := json.Unmarshal(updated, newRoot); err != nil {
+		return err
+	}
+	s.root = newRoot
+	return nil
+}
+
+// Save writes the current in-memory settings back to disk. The write is
+// atomic (temp file + fsync + rename) and guarded by an OS-level advisory
+// lock so a concurrent writer can't interleave with it, and the previous
+// contents are snapshotted to the settings backup directory first; see
+// claude_settings_backup.go.
+//
+// Save alone only locks the write: a caller that did Load, then Patch,
+// then Save is still racing any other caller doing the same, since both
+// may have loaded the same pre-change snapshot before either's lock was
+// held. Callers that read-modify-write should use Update instead, which
+// holds the lock for the whole cycle.
+func (s *ClaudeSettingsStore) Save() error {
+	if s.root == nil {
+		return fmt.Errorf("settings not loaded")
+	}
+	return withFileLock(s.path, s.saveUnlocked)
+}
+
+func (s *ClaudeSettingsStore) saveUnlocked() error {
+	if dir := filepath.Dir(s.path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("create settings directory: %w", err)
+		}
+	}
+
+	output, err := json.MarshalIndent(s.root, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal settings: %w", err)
+	}
+
+	if err := backupSettingsFile(s.path); err != nil {
+		return fmt.Errorf("backup settings file: %w", err)
+	}
+	return atomicWriteFile(s.path, output, 0644)
+}
+
+// Update runs a single OS-level-locked read-modify-write cycle against the
+// settings file: it (re)loads the latest contents from disk, calls fn to
+// apply changes via Patch/Delete, and saves the result, all under one
+// lock. This is the safe way for a handler to change settings.json,
+// because two concurrent Update calls can't both load the same
+// pre-change snapshot and silently clobber each other's write.
+func (s *ClaudeSettingsStore) Update(fn func(*ClaudeSettingsStore) error) error {
+	return withFileLock(s.path, func() error {
+		if err := s.loadUnlocked(); err != nil {
+			return err
+		}
+		if err := fn(s); err != nil {
+			return err
+		}
+		return s.saveUnlocked()
+	})
+}
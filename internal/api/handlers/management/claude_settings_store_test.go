@@ -0,0 +1,96 @@
+package management
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSplitPath(t *testing.T) {
+	cases := []struct {
+		path string
+		want []pathSegment
+	}{
+		{"opus_model", []pathSegment{{key: "opus_model"}}},
+		{"env.ANTHROPIC_API_KEY", []pathSegment{{key: "env"}, {key: "ANTHROPIC_API_KEY"}}},
+		{"permissions.allow[0]", []pathSegment{{key: "permissions"}, {key: "allow"}, {index: 0, isIndex: true}}},
+		{"a[0][1]", []pathSegment{{key: "a"}, {index: 0, isIndex: true}, {index: 1, isIndex: true}}},
+	}
+
+	for _, tc := range cases {
+		got := splitPath(tc.path)
+		if len(got) != len(tc.want) {
+			t.Fatalf("splitPath(%q) = %+v, want %+v", tc.path, got, tc.want)
+		}
+		for i := range got {
+			if got[i] != tc.want[i] {
+				t.Fatalf("splitPath(%q)[%d] = %+v, want %+v", tc.path, i, got[i], tc.want[i])
+			}
+		}
+	}
+}
+
+func TestOrderedMapPreservesKeyOrder(t *testing.T) {
+	input := `{"z": 1, "a": 2, "m": {"nested": true}}`
+
+	m := newOrderedMap()
+	if err := json.Unmarshal([]byte(input), m); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	if got := m.keys; len(got) != 3 || got[0] != "z" || got[1] != "a" || got[2] != "m" {
+		t.Fatalf("key order = %v, want [z a m]", got)
+	}
+
+	out, err := m.MarshalJSON()
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if string(out) != `{"z":1,"a":2,"m":{"nested": true}}` {
+		t.Fatalf("MarshalJSON() = %s", out)
+	}
+}
+
+func TestClaudeSettingsStoreGetPatchDelete(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.json")
+	if err := os.WriteFile(path, []byte(`{"unknown_field": "keep-me", "opus_model": "old"}`), 0644); err != nil {
+		t.Fatalf("seed settings file: %v", err)
+	}
+
+	store := NewClaudeSettingsStore(path)
+	if err := store.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if err := store.Patch("env.ANTHROPIC_API_KEY", "sk-test"); err != nil {
+		t.Fatalf("Patch: %v", err)
+	}
+	if err := store.Patch("permissions.allow[0]", "Bash(npm run test:*)"); err != nil {
+		t.Fatalf("Patch: %v", err)
+	}
+	if err := store.Delete("opus_model"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	reloaded := NewClaudeSettingsStore(path)
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+
+	if raw, ok, err := reloaded.Get("unknown_field"); err != nil || !ok || string(raw) != `"keep-me"` {
+		t.Fatalf("unknown_field = %s, ok=%v, err=%v, want \"keep-me\"", raw, ok, err)
+	}
+	if raw, ok, err := reloaded.Get("env.ANTHROPIC_API_KEY"); err != nil || !ok || string(raw) != `"sk-test"` {
+		t.Fatalf("env.ANTHROPIC_API_KEY = %s, ok=%v, err=%v", raw, ok, err)
+	}
+	if raw, ok, err := reloaded.Get("permissions.allow[0]"); err != nil || !ok || string(raw) != `"Bash(npm run test:*)"` {
+		t.Fatalf("permissions.allow[0] = %s, ok=%v, err=%v", raw, ok, err)
+	}
+	if _, ok, _ := reloaded.Get("opus_model"); ok {
+		t.Fatalf("opus_model should have been deleted")
+	}
+}